@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// TestExtractDocumentLinks checks that document links are picked up from
+// href/src attributes, resolved against the base URL, filtered by
+// extension case-insensitively, and deduplicated.
+func TestExtractDocumentLinks(t *testing.T) {
+	html := `
+		<a href="/sds/sheet1.PDF">SDS 1</a>
+		<a href="sheet2.docx?rev=2">SDS 2</a>
+		<iframe src="/embed/sheet3.xlsx"></iframe>
+		<embed src="/embed/sheet3.xlsx">
+		<a href="/page/about">About</a>
+		<a href="mailto:someone@example.com">Contact</a>
+	`
+	baseURL, err := url.Parse("https://millcraft.example/docs/index.html")
+	if err != nil {
+		t.Fatalf("failed to parse base URL: %v", err)
+	}
+
+	got := extractDocumentLinks(html, baseURL, defaultDocumentExtensions)
+	want := []string{
+		"https://millcraft.example/sds/sheet1.PDF",
+		"https://millcraft.example/docs/sheet2.docx?rev=2",
+		"https://millcraft.example/embed/sheet3.xlsx",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractDocumentLinks() = %v, want %v", got, want)
+	}
+}
+
+// TestExtractDocumentLinksNarrowerExtensionSet checks that passing a
+// narrower exts slice excludes extensions outside of it.
+func TestExtractDocumentLinksNarrowerExtensionSet(t *testing.T) {
+	html := `<a href="/a.pdf">A</a><a href="/b.csv">B</a>`
+	baseURL, err := url.Parse("https://millcraft.example/")
+	if err != nil {
+		t.Fatalf("failed to parse base URL: %v", err)
+	}
+
+	got := extractDocumentLinks(html, baseURL, []string{".pdf"})
+	want := []string{"https://millcraft.example/a.pdf"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractDocumentLinks() = %v, want %v", got, want)
+	}
+}
+
+// TestExtractPageLinks checks that extractPageLinks resolves anchors
+// against the base URL while excluding anything extractDocumentLinks
+// would already claim.
+func TestExtractPageLinks(t *testing.T) {
+	html := `
+		<a href="/products">Products</a>
+		<a href="/sds/sheet1.pdf">SDS 1</a>
+		<a href="https://other.example/page">Other</a>
+	`
+	baseURL, err := url.Parse("https://millcraft.example/")
+	if err != nil {
+		t.Fatalf("failed to parse base URL: %v", err)
+	}
+
+	got := extractPageLinks(html, baseURL)
+	want := []string{
+		"https://millcraft.example/products",
+		"https://other.example/page",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractPageLinks() = %v, want %v", got, want)
+	}
+}
@@ -2,21 +2,33 @@ package main // Declare the main package
 
 // Import required standard library packages
 import (
-	"bytes"         // Provides buffer for reading/writing data
-	"context"       // For managing context in goroutines
-	"fmt"           // For formatted I/O
-	"io"            // For general I/O primitives
-	"log"           // For logging errors or info
-	"net/http"      // For making HTTP requests
-	"net/url"       // For parsing and manipulating URLs
-	"os"            // For file and system operations
-	"path/filepath" // For manipulating filename paths
-	"regexp"        // For using regular expressions
-	"strings"       // For string manipulation
-	"sync"          // For handling concurrency
-	"time"          // For time-related operations
+	"bufio"           // For efficient buffered reads over the on-disk queue
+	"bytes"           // For building WARC record payloads in memory
+	"compress/gzip"   // For per-record gzip framing in WARC output
+	"context"         // For managing context in goroutines
+	"crypto/rand"     // For generating WARC record IDs
+	"crypto/sha256"   // For hashing downloaded PDFs
+	"encoding/binary" // For encoding the length-prefixed queue records
+	"encoding/hex"    // For formatting the SHA-256 sidecar checksum
+	"encoding/json"   // For serving the dashboard's status as JSON
+	"flag"            // For parsing command line flags
+	"fmt"             // For formatted I/O
+	"io"              // For general I/O primitives
+	"log"             // For logging errors or info
+	"net/http"        // For making HTTP requests
+	"net/url"         // For parsing and manipulating URLs
+	"os"              // For file and system operations
+	"os/signal"       // For catching Ctrl+C to shut the dashboard down cleanly
+	"path/filepath"   // For manipulating filename paths
+	"strings"         // For string manipulation
+	"sync"            // For handling concurrency
+	"sync/atomic"     // For lock-free counters shared with the dashboard
+	"syscall"         // For naming the SIGTERM signal
+	"time"            // For time-related operations
 
 	"github.com/chromedp/chromedp"
+	"golang.org/x/net/html"
+	"golang.org/x/time/rate"
 )
 
 // removeDuplicatesFromSlice removes duplicate strings from a slice
@@ -119,12 +131,26 @@ func urlToFilename(rawURL string) string {
 	for _, char := range invalidChars {
 		filename = strings.ReplaceAll(filename, char, "_") // Replace invalid characters
 	}
-	if getFileExtension(filename) != ".pdf" {
-		filename = filename + ".pdf" // Ensure file ends with .pdf
+	if !hasDocumentExtension(parsed.Path, defaultDocumentExtensions) { // Check the URL's own path, not the query-polluted combined filename
+		filename = filename + ".pdf" // URL didn't carry a recognized document extension; default to .pdf as before
 	}
 	return strings.ToLower(filename) // Return sanitized and lowercased filename
 }
 
+// documentExtensionFromURL returns the lowercased document extension implied
+// by rawURL's path (e.g. ".docx" for ".../sheet2.docx?rev=2"), or ".pdf" if
+// the path doesn't carry one of defaultDocumentExtensions. It's used instead
+// of inspecting the sanitized filename, since query strings get folded into
+// that filename after the real extension and would throw off a naive
+// filepath.Ext lookup.
+func documentExtensionFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || !hasDocumentExtension(parsed.Path, defaultDocumentExtensions) {
+		return ".pdf"
+	}
+	return strings.ToLower(getFileExtension(parsed.Path))
+}
+
 // getFileExtension returns the file extension
 func getFileExtension(path string) string {
 	return filepath.Ext(path) // Use filepath to extract extension
@@ -188,59 +214,552 @@ func scrapePageHTMLWithChrome(pageURL string) string {
 	return pageHTML
 }
 
-// downloadPDF downloads a PDF from a URL and saves it to outputDir
-func downloadPDF(finalURL, outputDir string, waitGroup *sync.WaitGroup) {
-	defer waitGroup.Done()
+// WARCWriter appends WARC 1.1 records to an on-disk archive, one gzip
+// member per record so the resulting file stays seekable by standard WARC
+// tooling. A warcinfo record is written once, up front; WriteExchange then
+// appends a request/response pair per download.
+type WARCWriter struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// NewWARCWriter creates path and writes the leading warcinfo record.
+func NewWARCWriter(path string) (*WARCWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &WARCWriter{file: file}
+	warcinfoBody := []byte("software: millcraft-com-documentation crawler\r\nformat: WARC File Format 1.1\r\n")
+	if err := writer.writeRecord("warcinfo", newWARCRecordID(), [][2]string{
+		{"Content-Type", "application/warc-fields"},
+	}, warcinfoBody, ""); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return writer, nil
+}
+
+// WriteExchange appends a request record and its matching response record
+// for one download: the response payload is a status line and headers
+// rebuilt from resp (header order is not preserved), followed by the body
+// downloadPDF received.
+func (writer *WARCWriter) WriteExchange(targetURL string, resp *http.Response, body []byte) error {
+	requestID := newWARCRecordID()
+
+	host := ""
+	if parsed, err := url.Parse(targetURL); err == nil {
+		host = parsed.Host
+	}
+	requestPayload := []byte(fmt.Sprintf("GET %s HTTP/1.1\r\nHost: %s\r\n\r\n", targetURL, host))
+	if err := writer.writeRecord("request", requestID, [][2]string{
+		{"Content-Type", "application/http;msgtype=request"},
+	}, requestPayload, targetURL); err != nil {
+		return err
+	}
+
+	var headerBuf bytes.Buffer
+	fmt.Fprintf(&headerBuf, "HTTP/%d.%d %s\r\n", resp.ProtoMajor, resp.ProtoMinor, resp.Status)
+	for key, values := range resp.Header {
+		for _, value := range values {
+			fmt.Fprintf(&headerBuf, "%s: %s\r\n", key, value)
+		}
+	}
+	headerBuf.WriteString("\r\n")
+	responsePayload := append(headerBuf.Bytes(), body...)
+
+	return writer.writeRecord("response", newWARCRecordID(), [][2]string{
+		{"Content-Type", "application/http;msgtype=response"},
+		{"WARC-Concurrent-To", "<urn:uuid:" + requestID + ">"},
+	}, responsePayload, targetURL)
+}
+
+// writeRecord serializes one WARC record and writes it as its own gzip
+// member.
+func (writer *WARCWriter) writeRecord(recordType, recordID string, extraHeaders [][2]string, payload []byte, targetURI string) error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	var headerBuf bytes.Buffer
+	fmt.Fprintf(&headerBuf, "WARC/1.1\r\n")
+	fmt.Fprintf(&headerBuf, "WARC-Type: %s\r\n", recordType)
+	fmt.Fprintf(&headerBuf, "WARC-Record-ID: <urn:uuid:%s>\r\n", recordID)
+	fmt.Fprintf(&headerBuf, "WARC-Date: %s\r\n", time.Now().UTC().Format("2006-01-02T15:04:05Z"))
+	if targetURI != "" {
+		fmt.Fprintf(&headerBuf, "WARC-Target-URI: %s\r\n", targetURI)
+	}
+	fmt.Fprintf(&headerBuf, "Content-Length: %d\r\n", len(payload))
+	for _, header := range extraHeaders {
+		fmt.Fprintf(&headerBuf, "%s: %s\r\n", header[0], header[1])
+	}
+	headerBuf.WriteString("\r\n")
+
+	gzipWriter := gzip.NewWriter(writer.file) // One gzip member per record keeps the archive seekable record-by-record
+	if _, err := gzipWriter.Write(headerBuf.Bytes()); err != nil {
+		gzipWriter.Close()
+		return err
+	}
+	if _, err := gzipWriter.Write(payload); err != nil {
+		gzipWriter.Close()
+		return err
+	}
+	if _, err := gzipWriter.Write([]byte("\r\n\r\n")); err != nil { // WARC records are terminated by two CRLFs
+		gzipWriter.Close()
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// Close flushes and closes the underlying WARC file.
+func (writer *WARCWriter) Close() error {
+	return writer.file.Close()
+}
+
+// newWARCRecordID generates a random (version 4) UUID for a WARC-Record-ID.
+func newWARCRecordID() string {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		log.Printf("failed to generate random WARC record ID: %v", err)
+	}
+	raw[6] = (raw[6] & 0x0f) | 0x40 // Version 4
+	raw[8] = (raw[8] & 0x3f) | 0x80 // Variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", raw[0:4], raw[4:6], raw[6:8], raw[8:10], raw[10:16])
+}
+
+// documentTypeSpec describes how to validate a downloaded document of a
+// given extension: which Content-Type values are acceptable, and which
+// magic bytes (if any) the body must start with.
+type documentTypeSpec struct {
+	contentTypeSubstrings []string // Download is rejected unless the Content-Type header contains one of these
+	magicBytes            []byte   // Download is rejected unless the body starts with these bytes; nil skips the check
+}
+
+// documentTypesByExtension holds a documentTypeSpec for every extension in
+// defaultDocumentExtensions, so downloadPDF can validate each document kind
+// on its own terms instead of assuming everything is a PDF.
+var documentTypesByExtension = map[string]documentTypeSpec{
+	".pdf": {
+		contentTypeSubstrings: []string{"application/pdf"},
+		magicBytes:            []byte("%PDF"),
+	},
+	".doc": {
+		contentTypeSubstrings: []string{"application/msword"},
+		magicBytes:            []byte{0xD0, 0xCF, 0x11, 0xE0}, // OLE2 compound file header
+	},
+	".docx": {
+		contentTypeSubstrings: []string{"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip"},
+		magicBytes:            []byte("PK\x03\x04"), // OOXML files are ZIP archives
+	},
+	".xlsx": {
+		contentTypeSubstrings: []string{"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/zip"},
+		magicBytes:            []byte("PK\x03\x04"), // OOXML files are ZIP archives
+	},
+	".csv": {
+		contentTypeSubstrings: []string{"text/csv", "application/csv", "text/plain"},
+		magicBytes:            nil, // Plain text has no reliable magic bytes to check
+	},
+}
+
+// downloadPDF downloads a document from a URL and saves it to outputDir. It
+// returns the number of bytes written on success, or a non-nil error
+// describing why the download didn't produce a file. The Content-Type and
+// (if applicable) magic-byte checks are chosen based on the extension the
+// source URL's path carries, not the sanitized filename (which can fold a
+// query string in after the real extension). When warcWriter is non-nil,
+// the response is also archived as a WARC request/response pair.
+func downloadPDF(finalURL, outputDir string, warcWriter *WARCWriter) (int64, error) {
 	filename := strings.ToLower(urlToFilename(finalURL)) // Create sanitized filename
 	filePath := filepath.Join(outputDir, filename)       // Combine with output directory
 
 	if fileExists(filePath) {
 		log.Printf("file already exists, skipping: %s", filePath)
-		return
+		return 0, nil
+	}
+
+	spec, ok := documentTypesByExtension[documentExtensionFromURL(finalURL)]
+	if !ok {
+		spec = documentTypesByExtension[".pdf"] // Fall back to the original PDF-only behavior for an unrecognized extension
 	}
 
 	client := &http.Client{Timeout: 30 * time.Second} // HTTP client with timeout
 	resp, err := client.Get(finalURL)                 // Send HTTP GET
 	if err != nil {
-		log.Printf("failed to download %s: %v", finalURL, err)
-		return
+		err = fmt.Errorf("failed to download %s: %w", finalURL, err)
+		log.Println(err)
+		return 0, err
 	}
 	defer resp.Body.Close() // Ensure response body is closed
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("download failed for %s: %s", finalURL, resp.Status)
-		return
+		err := fmt.Errorf("download failed for %s: %s", finalURL, resp.Status)
+		log.Println(err)
+		return 0, err
 	}
 
 	contentType := resp.Header.Get("Content-Type") // Get content-type header
-	if !strings.Contains(contentType, "application/pdf") {
-		log.Printf("invalid content type for %s: %s (expected application/pdf)", finalURL, contentType)
-		return
+	if !containsAny(contentType, spec.contentTypeSubstrings) {
+		err := fmt.Errorf("invalid content type for %s: %s (expected one of %v)", finalURL, contentType, spec.contentTypeSubstrings)
+		log.Println(err)
+		return 0, err
 	}
 
-	var buf bytes.Buffer                     // Create buffer
-	written, err := io.Copy(&buf, resp.Body) // Copy response body to buffer
+	partialPath := filePath + ".part" // Stream into a temp file so a crash mid-download never leaves a half-written document at filePath
+	out, err := os.Create(partialPath)
 	if err != nil {
-		log.Printf("failed to read PDF data from %s: %v", finalURL, err)
-		return
+		err = fmt.Errorf("failed to create temp file for %s: %w", finalURL, err)
+		log.Println(err)
+		return 0, err
+	}
+
+	hasher := sha256.New() // Accumulates a checksum as the body streams past
+	writers := []io.Writer{out, hasher}
+	var warcBody *bytes.Buffer
+	if warcWriter != nil {
+		warcBody = &bytes.Buffer{} // Only buffer the body in memory when archival output was actually requested
+		writers = append(writers, warcBody)
+	}
+	written, err := io.Copy(io.MultiWriter(writers...), resp.Body) // Stream straight to disk instead of buffering in memory
+	out.Close()
+	if err != nil {
+		err = fmt.Errorf("failed to stream PDF data from %s: %w", finalURL, err)
+		log.Println(err)
+		removeFile(partialPath)
+		return 0, err
 	}
 	if written == 0 {
-		log.Printf("downloaded 0 bytes for %s; not creating file", finalURL)
-		return
+		err := fmt.Errorf("downloaded 0 bytes for %s; not creating file", finalURL)
+		log.Println(err)
+		removeFile(partialPath)
+		return 0, err
+	}
+
+	if spec.magicBytes != nil && !fileStartsWithMagic(partialPath, spec.magicBytes) {
+		err := fmt.Errorf("downloaded file for %s does not start with the expected magic bytes; likely an error page", finalURL)
+		log.Println(err)
+		removeFile(partialPath)
+		return 0, err
+	}
+
+	if err := os.Rename(partialPath, filePath); err != nil { // Commit atomically: the final path only ever holds a fully-verified document
+		err = fmt.Errorf("failed to finalize downloaded file for %s: %w", finalURL, err)
+		log.Println(err)
+		removeFile(partialPath)
+		return 0, err
+	}
+
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	sidecarPath := filePath + ".sha256"
+	if err := appendByteToFile(sidecarPath, []byte(checksum+"  "+filename+"\n")); err != nil {
+		log.Printf("failed to write checksum sidecar for %s: %v", finalURL, err)
+	}
+
+	if warcWriter != nil {
+		if err := warcWriter.WriteExchange(finalURL, resp, warcBody.Bytes()); err != nil {
+			log.Printf("failed to write WARC record for %s: %v", finalURL, err)
+		}
+	}
+
+	return written, nil
+}
+
+// fileStartsWithMagic reports whether path begins with magic, guarding
+// against HTML error pages served with a misleading Content-Type.
+func fileStartsWithMagic(path string, magic []byte) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	buf := make([]byte, len(magic))
+	if _, err := io.ReadFull(file, buf); err != nil {
+		return false
 	}
+	return bytes.Equal(buf, magic)
+}
+
+// containsAny reports whether s contains any of substrings.
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// Downloader is a bounded worker pool for downloadPDF jobs, with a separate
+// token-bucket rate limiter per destination host so a big crawl can't
+// hammer any single origin.
+type Downloader struct {
+	Workers    int              // Number of worker goroutines processing jobs
+	PerHostQPS float64          // Sustained requests per second allowed to any one host
+	Burst      int              // Burst size allowed on top of PerHostQPS
+	OutputDir  string           // Directory downloaded PDFs are written to
+	WARCWriter *WARCWriter      // Optional archival sink; nil disables WARC output
+	OnComplete func(url string) // Optional hook invoked after each job succeeds, e.g. to mark a VisitQueue entry done
+
+	jobs         chan string
+	waitGroup    sync.WaitGroup
+	limiterMutex sync.Mutex
+	limiters     map[string]*rate.Limiter
+
+	paused     atomic.Bool // Checked by every worker after it takes a job and before it starts the download
+	pauseMutex sync.Mutex  // Guards pauseCond's wait/broadcast
+	pauseCond  *sync.Cond  // Woken by Resume so paused workers don't busy-loop
+
+	bytesDownloaded atomic.Int64
+	completedCount  atomic.Int64
+	failedMutex     sync.Mutex
+	failed          map[string]string // URL -> failure reason, for dashboard inspection
+}
+
+// NewDownloader builds a Downloader and starts its worker goroutines.
+func NewDownloader(outputDir string, workers int, perHostQPS float64, burst int) *Downloader {
+	downloader := &Downloader{
+		Workers:    workers,
+		PerHostQPS: perHostQPS,
+		Burst:      burst,
+		OutputDir:  outputDir,
+		jobs:       make(chan string, workers*2), // Small buffer so Enqueue rarely blocks on a slow worker
+		limiters:   make(map[string]*rate.Limiter),
+		failed:     make(map[string]string),
+	}
+	downloader.pauseCond = sync.NewCond(&downloader.pauseMutex)
+	for i := 0; i < workers; i++ {
+		go downloader.worker()
+	}
+	return downloader
+}
+
+// worker pulls jobs until the jobs channel is closed, rate-limiting each one
+// by destination host before handing it to downloadPDF.
+func (downloader *Downloader) worker() {
+	for job := range downloader.jobs {
+		downloader.waitIfPaused()
+		if err := downloader.limiterFor(job).Wait(context.Background()); err != nil {
+			log.Printf("rate limiter wait failed for %s: %v", job, err)
+		}
+		written, err := downloadPDF(job, downloader.OutputDir, downloader.WARCWriter)
+		downloader.recordResult(job, written, err)
+		if err == nil && downloader.OnComplete != nil { // Only mark successes done; a failed job must stay eligible for retry on resume
+			downloader.OnComplete(job)
+		}
+		downloader.waitGroup.Done()
+	}
+}
 
-	out, err := os.Create(filePath) // Create output file
+// waitIfPaused blocks the calling worker while the downloader is paused.
+func (downloader *Downloader) waitIfPaused() {
+	if !downloader.paused.Load() {
+		return // Fast path: no lock needed when running normally
+	}
+	downloader.pauseMutex.Lock()
+	for downloader.paused.Load() {
+		downloader.pauseCond.Wait()
+	}
+	downloader.pauseMutex.Unlock()
+}
+
+// Pause stops workers from starting new downloads until Resume is called;
+// each worker may hold one already-received job while it waits. Jobs
+// already in flight are allowed to finish.
+func (downloader *Downloader) Pause() {
+	downloader.paused.Store(true)
+}
+
+// Resume wakes any workers blocked by a prior Pause.
+func (downloader *Downloader) Resume() {
+	downloader.paused.Store(false)
+	downloader.pauseMutex.Lock()
+	downloader.pauseCond.Broadcast()
+	downloader.pauseMutex.Unlock()
+}
+
+// recordResult updates the downloader's stats after a job finishes.
+func (downloader *Downloader) recordResult(rawURL string, written int64, err error) {
 	if err != nil {
-		log.Printf("failed to create file for %s: %v", finalURL, err)
+		downloader.failedMutex.Lock()
+		downloader.failed[rawURL] = err.Error()
+		downloader.failedMutex.Unlock()
+		return
+	}
+	downloader.completedCount.Add(1)
+	downloader.bytesDownloaded.Add(written)
+}
+
+// DownloaderStats is a point-in-time snapshot of a Downloader's progress,
+// suitable for serializing onto the dashboard.
+type DownloaderStats struct {
+	BytesDownloaded int64             `json:"bytesDownloaded"`
+	Completed       int64             `json:"completed"`
+	Failed          map[string]string `json:"failed"`
+	PerHostQPS      float64           `json:"perHostQPS"`
+	Burst           int               `json:"burst"`
+	Paused          bool              `json:"paused"`
+}
+
+// Snapshot returns the downloader's current stats.
+func (downloader *Downloader) Snapshot() DownloaderStats {
+	downloader.failedMutex.Lock()
+	failedCopy := make(map[string]string, len(downloader.failed))
+	for failedURL, reason := range downloader.failed {
+		failedCopy[failedURL] = reason
+	}
+	downloader.failedMutex.Unlock()
+
+	return DownloaderStats{
+		BytesDownloaded: downloader.bytesDownloaded.Load(),
+		Completed:       downloader.completedCount.Load(),
+		Failed:          failedCopy,
+		PerHostQPS:      downloader.PerHostQPS,
+		Burst:           downloader.Burst,
+		Paused:          downloader.paused.Load(),
+	}
+}
+
+// limiterFor returns the shared rate.Limiter for rawURL's host, creating one
+// on first use.
+func (downloader *Downloader) limiterFor(rawURL string) *rate.Limiter {
+	host := ""
+	if parsed, err := url.Parse(rawURL); err == nil {
+		host = parsed.Host
+	}
+
+	downloader.limiterMutex.Lock()
+	defer downloader.limiterMutex.Unlock()
+
+	limiter, ok := downloader.limiters[host]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(downloader.PerHostQPS), downloader.Burst)
+		downloader.limiters[host] = limiter
+	}
+	return limiter
+}
+
+// Enqueue schedules rawURL for download, blocking only if every worker is
+// already busy and the job buffer is full.
+func (downloader *Downloader) Enqueue(rawURL string) {
+	downloader.waitGroup.Add(1)
+	downloader.jobs <- rawURL
+}
+
+// Wait blocks until every enqueued job has completed.
+func (downloader *Downloader) Wait() {
+	downloader.waitGroup.Wait()
+}
+
+// Dashboard serves a small HTTP control surface for an in-progress crawl,
+// so an operator can inspect progress and pause/resume/enqueue without
+// killing the process.
+type Dashboard struct {
+	downloader *Downloader
+	visitQueue *VisitQueue
+}
+
+// NewDashboard wires up a Dashboard around an already-running downloader
+// and visit queue.
+func NewDashboard(downloader *Downloader, visitQueue *VisitQueue) *Dashboard {
+	return &Dashboard{downloader: downloader, visitQueue: visitQueue}
+}
+
+// Start begins serving the dashboard on addr and returns the underlying
+// *http.Server so the caller can Shutdown it if needed.
+func (dashboard *Dashboard) Start(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", dashboard.handleStatus)
+	mux.HandleFunc("/pause", dashboard.handlePause)
+	mux.HandleFunc("/resume", dashboard.handleResume)
+	mux.HandleFunc("/enqueue", dashboard.handleEnqueue)
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("dashboard server error: %v", err)
+		}
+	}()
+	log.Printf("dashboard listening on %s", addr)
+	return server
+}
+
+// dashboardStatus is the JSON shape returned by GET /status.
+type dashboardStatus struct {
+	QueueDepth      int64             `json:"queueDepth"`
+	Completed       int               `json:"completed"`
+	BytesDownloaded int64             `json:"bytesDownloaded"`
+	Failed          map[string]string `json:"failed"`
+	PerHostQPS      float64           `json:"perHostQPS"`
+	Paused          bool              `json:"paused"`
+}
+
+// handleStatus reports queue depth, completed count, failed URLs with
+// reasons, bytes downloaded, and the configured per-host QPS.
+func (dashboard *Dashboard) handleStatus(response http.ResponseWriter, request *http.Request) {
+	pushed, completedInQueue := dashboard.visitQueue.Stats()
+	stats := dashboard.downloader.Snapshot()
+
+	status := dashboardStatus{
+		QueueDepth:      pushed - int64(completedInQueue),
+		Completed:       completedInQueue,
+		BytesDownloaded: stats.BytesDownloaded,
+		Failed:          stats.Failed,
+		PerHostQPS:      stats.PerHostQPS,
+		Paused:          stats.Paused,
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(response).Encode(status); err != nil {
+		log.Printf("failed to encode dashboard status: %v", err)
+	}
+}
+
+// handlePause pauses the worker pool; in-flight jobs are left to finish.
+func (dashboard *Dashboard) handlePause(response http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(response, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	dashboard.downloader.Pause()
+	fmt.Fprintln(response, "paused")
+}
+
+// handleResume resumes a previously-paused worker pool.
+func (dashboard *Dashboard) handleResume(response http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(response, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	defer out.Close() // Close file
+	dashboard.downloader.Resume()
+	fmt.Fprintln(response, "resumed")
+}
 
-	_, err = buf.WriteTo(out) // Write buffer to file
+// handleEnqueue accepts a raw URL in the POST body and injects it into both
+// the visit queue (so it survives a restart) and the live worker pool.
+func (dashboard *Dashboard) handleEnqueue(response http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(response, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(request.Body)
 	if err != nil {
-		log.Printf("failed to write PDF to file for %s: %v", finalURL, err)
+		http.Error(response, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	seedURL := strings.TrimSpace(string(body))
+	if !isUrlValid(seedURL) {
+		http.Error(response, "invalid URL", http.StatusBadRequest)
 		return
 	}
+
+	if err := dashboard.visitQueue.Push(seedURL); err != nil {
+		log.Printf("failed to persist enqueued URL %s: %v", seedURL, err)
+	}
+	dashboard.downloader.Enqueue(seedURL)
+	fmt.Fprintln(response, "enqueued")
 }
 
 // directoryExists checks whether a directory exists
@@ -260,22 +779,313 @@ func createDirectory(path string, permission os.FileMode) {
 	}
 }
 
-// extractPDFLinks scans HTML and extracts all unique .pdf links
-func extractPDFLinks(htmlContent string) []string {
-	pdfRegex := regexp.MustCompile(`href=["']([^"']+\.pdf)["']`) // Regex to find .pdf URLs
-	seen := make(map[string]struct{})                            // Track seen links
+// defaultDocumentExtensions is the set of file extensions extractDocumentLinks
+// looks for when the caller doesn't need a narrower set.
+var defaultDocumentExtensions = []string{".pdf", ".doc", ".docx", ".xlsx", ".csv"}
+
+// extractDocumentLinks scans htmlContent for <a href> and <iframe>/<embed src>
+// targets whose resolved path ends in one of exts, resolving relative URLs
+// against baseURL. It uses a real tokenizer instead of regex so malformed or
+// minified markup, query strings after the extension, and attributes split
+// across lines are all handled correctly.
+func extractDocumentLinks(htmlContent string, baseURL *url.URL, exts []string) []string {
+	var links []string
+	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent)) // Tokenize the HTML stream
+
+	for {
+		tokenType := tokenizer.Next() // Advance to the next token
+		if tokenType == html.ErrorToken {
+			break // Reached EOF or a parse error; stop walking
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue // Only tags can carry the attribute we care about
+		}
+
+		token := tokenizer.Token()
+		var attrKey string
+		switch token.Data {
+		case "a":
+			attrKey = "href"
+		case "iframe", "embed":
+			attrKey = "src"
+		default:
+			continue // Documents are only ever linked from these tags
+		}
+
+		for _, attr := range token.Attr {
+			if attr.Key != attrKey {
+				continue
+			}
+			resolved, err := resolveAgainstBase(baseURL, attr.Val)
+			if err != nil {
+				continue // Skip targets that aren't usable URLs (mailto:, javascript:, etc.)
+			}
+			if hasDocumentExtension(resolved.Path, exts) {
+				links = append(links, resolved.String())
+			}
+		}
+	}
+
+	return removeDuplicatesFromSlice(links)
+}
+
+// hasDocumentExtension reports whether path's extension case-insensitively
+// matches one of exts.
+func hasDocumentExtension(path string, exts []string) bool {
+	pathExt := getFileExtension(path)
+	for _, ext := range exts {
+		if strings.EqualFold(pathExt, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractPageLinks scans HTML and returns every absolute anchor target it
+// can find that doesn't itself point at a document, resolved against
+// baseURL. It uses a real tokenizer instead of regex so malformed or
+// minified markup is still handled.
+func extractPageLinks(htmlContent string, baseURL *url.URL) []string {
 	var links []string
+	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent)) // Tokenize the HTML stream
+
+	for {
+		tokenType := tokenizer.Next() // Advance to the next token
+		if tokenType == html.ErrorToken {
+			break // Reached EOF or a parse error; stop walking
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue // Only tags can carry an href we care about
+		}
+
+		token := tokenizer.Token()
+		if token.Data != "a" {
+			continue // Only anchor tags point at other pages
+		}
+
+		for _, attr := range token.Attr {
+			if attr.Key != "href" {
+				continue
+			}
+			resolved, err := resolveAgainstBase(baseURL, attr.Val)
+			if err != nil {
+				continue // Skip hrefs that aren't usable URLs (mailto:, javascript:, etc.)
+			}
+			if hasDocumentExtension(resolved.Path, defaultDocumentExtensions) {
+				continue // Documents are handled by extractDocumentLinks, not the page crawl
+			}
+			links = append(links, resolved.String())
+		}
+	}
+
+	return removeDuplicatesFromSlice(links) // Drop duplicate page links
+}
+
+// resolveAgainstBase resolves a possibly-relative href against baseURL.
+func resolveAgainstBase(baseURL *url.URL, href string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(href)) // Parse the raw href
+	if err != nil {
+		return nil, err
+	}
+	return baseURL.ResolveReference(parsed), nil // Turn it into an absolute URL
+}
+
+// crawlForPDFs walks the site starting at seed, following same-page-type
+// anchors up to maxDepth levels deep, and collects every document link
+// (any of defaultDocumentExtensions) it encounters along the way. When sameHostOnly is true, the crawl never
+// follows a link off of the seed's host.
+func crawlForPDFs(seed string, maxDepth int, sameHostOnly bool) []string {
+	seedURL, err := url.Parse(seed) // Parse the seed so we can resolve relative links and check hosts
+	if err != nil {
+		log.Printf("invalid seed URL %s: %v", seed, err)
+		return nil
+	}
+
+	type queueItem struct {
+		pageURL string
+		depth   int
+	}
+
+	visited := make(map[string]struct{}) // Pages already fetched, so we don't loop forever
+	queue := []queueItem{{seed, 0}}      // BFS frontier, seeded with the starting page
+	var pdfLinks []string                // PDF links collected from every page we visit
+
+	for len(queue) > 0 {
+		item := queue[0] // Pop the front of the queue (BFS order)
+		queue = queue[1:]
+
+		if _, ok := visited[item.pageURL]; ok {
+			continue // Already fetched this page
+		}
+		visited[item.pageURL] = struct{}{}
+
+		pageHTML := scrapePageHTMLWithChrome(item.pageURL) // Render the page and grab its HTML
+		if pageHTML == "" {
+			continue // Nothing we can extract from a failed fetch
+		}
+
+		pageURL, err := url.Parse(item.pageURL)
+		if err != nil {
+			continue
+		}
+		pdfLinks = append(pdfLinks, extractDocumentLinks(pageHTML, pageURL, defaultDocumentExtensions)...) // Harvest document links from this page
+
+		if item.depth >= maxDepth {
+			continue // Don't enqueue further pages past the configured depth
+		}
+
+		for _, link := range extractPageLinks(pageHTML, pageURL) {
+			linkURL, err := url.Parse(link)
+			if err != nil {
+				continue
+			}
+			if sameHostOnly && !strings.EqualFold(linkURL.Host, seedURL.Host) {
+				continue // Stay within the seed's host
+			}
+
+			_, alreadyVisited := visited[link]
+			if alreadyVisited {
+				continue
+			}
+			queue = append(queue, queueItem{link, item.depth + 1})
+		}
+	}
+
+	return removeDuplicatesFromSlice(pdfLinks) // Return every PDF link found across the crawl
+}
+
+// VisitQueue is a persistent, resumable queue of URLs to visit. Pending
+// entries live in an append-only, length-prefixed binary log (queueFilePath)
+// so the process can crash mid-run without losing anything still in flight,
+// and completed entries live in a plain newline-delimited file
+// (completedFilePath) so a restart can skip everything already handled.
+type VisitQueue struct {
+	mutex         sync.Mutex          // Guards queueFile and completed together
+	queueFile     *os.File            // Append handle for pushing new pending URLs
+	readFile      *os.File            // Separate, independent read handle used for Pop
+	reader        *bufio.Reader       // Buffers sequential reads over readFile
+	completedPath string              // Where completed URLs are recorded
+	completed     map[string]struct{} // In-memory mirror of completedPath, for fast lookups
+	pushedCount   atomic.Int64        // Total URLs ever pushed, for reporting queue depth
+}
+
+// NewVisitQueue opens (or creates) the queue and completed files at the
+// given paths and loads the completed set into memory.
+func NewVisitQueue(queueFilePath, completedFilePath string) (*VisitQueue, error) {
+	completed := make(map[string]struct{})
+	if fileExists(completedFilePath) {
+		for _, line := range strings.Split(readFileAndReturnAsString(completedFilePath), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" {
+				completed[line] = struct{}{} // Mark this URL as already handled in a previous run
+			}
+		}
+	}
+
+	queueFile, err := os.OpenFile(queueFilePath, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	readFile, err := os.Open(queueFilePath) // Independent handle so Pop's read cursor isn't affected by Push appends
+	if err != nil {
+		queueFile.Close()
+		return nil, err
+	}
 
-	for _, line := range strings.Split(htmlContent, "\n") { // Process each line
-		for _, match := range pdfRegex.FindAllString(line, -1) { // Find matches
-			if _, ok := seen[match]; !ok { // If link is new
-				seen[match] = struct{}{}     // Mark as seen
-				links = append(links, match) // Add to list
+	queue := &VisitQueue{
+		queueFile:     queueFile,
+		readFile:      readFile,
+		reader:        bufio.NewReader(readFile),
+		completedPath: completedFilePath,
+		completed:     completed,
+	}
+	if countFile, err := os.Open(queueFilePath); err == nil { // Count records left by a previous run so Stats stays accurate on resume
+		countReader, lengthPrefix := bufio.NewReader(countFile), make([]byte, 4)
+		for {
+			if _, err := io.ReadFull(countReader, lengthPrefix); err != nil {
+				break
+			}
+			if _, err := countReader.Discard(int(binary.BigEndian.Uint32(lengthPrefix))); err != nil {
+				break // Truncated trailing record; Pop won't return it either
 			}
+			queue.pushedCount.Add(1)
 		}
+		countFile.Close()
 	}
+	return queue, nil
+}
+
+// Push appends url to the on-disk pending queue as a length-prefixed record.
+func (queue *VisitQueue) Push(url string) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	data := []byte(url)
+	lengthPrefix := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthPrefix, uint32(len(data))) // Record how many bytes follow
+
+	if _, err := queue.queueFile.Write(lengthPrefix); err != nil {
+		return err
+	}
+	_, err := queue.queueFile.Write(data)
+	if err == nil {
+		queue.pushedCount.Add(1)
+	}
+	return err
+}
 
-	return links // Return list of links
+// Stats returns how many URLs have ever been pushed and how many have been
+// marked done, so a caller can derive the remaining queue depth.
+func (queue *VisitQueue) Stats() (pushed int64, completed int) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.pushedCount.Load(), len(queue.completed)
+}
+
+// Pop returns the next pending URL that hasn't already been marked done,
+// or ok == false once the queue is exhausted.
+func (queue *VisitQueue) Pop() (url string, ok bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	for {
+		lengthPrefix := make([]byte, 4)
+		if _, err := io.ReadFull(queue.reader, lengthPrefix); err != nil {
+			return "", false // Reached the end of everything ever pushed
+		}
+		length := binary.BigEndian.Uint32(lengthPrefix)
+		data := make([]byte, length)
+		if _, err := io.ReadFull(queue.reader, data); err != nil {
+			return "", false // Truncated record; treat it as the end of the queue
+		}
+
+		candidate := string(data)
+		if _, done := queue.completed[candidate]; done {
+			continue // Already handled in a previous run; skip to the next record
+		}
+		return candidate, true
+	}
+}
+
+// MarkDone records url as completed so future Pop calls (including after a
+// restart) skip it.
+func (queue *VisitQueue) MarkDone(url string) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	queue.completed[url] = struct{}{}
+	return appendByteToFile(queue.completedPath, []byte(url+"\n"))
+}
+
+// Close releases the queue's open file handles.
+func (queue *VisitQueue) Close() error {
+	readErr := queue.readFile.Close()
+	writeErr := queue.queueFile.Close()
+	if writeErr != nil {
+		return writeErr
+	}
+	return readErr
 }
 
 // removeFile deletes a file from the filesystem
@@ -288,44 +1098,124 @@ func removeFile(path string) {
 
 // main is the entry point of the program
 func main() {
+	seed := flag.String("seed", "https://millcraft.com/safety-data-sheets/", "seed URL to start crawling from")
+	depth := flag.Int("depth", 0, "how many link-hops past the seed page to follow (0 = seed page only)")
+	sameHostOnly := flag.Bool("samehost", true, "only follow links that stay on the seed's host")
+	workers := flag.Int("workers", 8, "number of concurrent download workers")
+	perHostQPS := flag.Float64("perhostqps", 1, "sustained requests per second allowed to any one host")
+	burst := flag.Int("burst", 2, "burst size allowed on top of perhostqps")
+	dashboardAddr := flag.String("dashboard", "", "if set, serve a control dashboard on this address (e.g. :8080)")
+	warcPath := flag.String("warc", "", "if set, also archive every download as WARC 1.1 records at this path (e.g. out.warc.gz)")
+	flag.Parse()
+
 	filename := "millcraft.html" // Filename to save scraped HTML
 
-	if fileExists(filename) {
-		// removeFile(filename) // Remove old version of file
-		log.Println("Skipping the removing the html file.")
+	queueFilePath := "queue.bin"          // On-disk pending queue, so a crash can resume
+	completedFilePath := "completed.txt"  // On-disk record of what's already been handled
+	resuming := fileExists(queueFilePath) // Detect a prior, unfinished run before we touch the file
+
+	var extractedURL []string // Store extracted document URLs
+	if !resuming {
+		// Only scrape and crawl on a fresh run; a resume already has everything
+		// it needs to find in queue.bin and must not redo a possibly long crawl.
+		if fileExists(filename) {
+			// removeFile(filename) // Remove old version of file
+			log.Println("Skipping the removing the html file.")
+		}
+
+		if !fileExists(filename) {
+			var websiteContent string
+			if isUrlValid(*seed) {
+				websiteContent = scrapePageHTMLWithChrome(*seed) // Render the seed page
+			}
+			// Save the HTML content to a file
+			appendByteToFile(filename, []byte(websiteContent)) // Save HTML content to file
+		}
+
+		if *depth > 0 {
+			extractedURL = crawlForPDFs(*seed, *depth, *sameHostOnly) // Follow links up to depth, collecting documents along the way
+		} else {
+			seedURL, err := url.Parse(*seed)
+			if err != nil {
+				log.Fatalf("invalid seed URL %s: %v", *seed, err)
+			}
+			fileContent := readFileAndReturnAsString(filename)                                   // Read saved HTML
+			extractedURL = extractDocumentLinks(fileContent, seedURL, defaultDocumentExtensions) // Extract document links from the seed page only
+		}
+		extractedURL = removeDuplicatesFromSlice(extractedURL) // Remove duplicate links
 	}
 
-	if !fileExists(filename) {
-		url := "https://millcraft.com/safety-data-sheets/" // URL to scrape
-		var websiteContent string
-		if isUrlValid(url) {
-			websiteContent = scrapePageHTMLWithChrome(url) // Download in goroutine
+	visitQueue, err := NewVisitQueue(queueFilePath, completedFilePath)
+	if err != nil {
+		log.Fatalf("failed to open visit queue: %v", err)
+	}
+	defer visitQueue.Close()
+
+	if resuming {
+		log.Println("Found an existing queue.bin; resuming and skipping URLs already in completed.txt")
+	} else {
+		for _, url := range extractedURL {
+			if err := visitQueue.Push(url); err != nil {
+				log.Printf("failed to queue %s: %v", url, err)
+			}
 		}
-		// Save the HTML content to a file
-		appendByteToFile(filename, []byte(websiteContent)) // Save HTML content to file
 	}
 
-	var extractedURL []string                              // Store extracted PDF URLs
-	fileContent := readFileAndReturnAsString(filename)     // Read saved HTML
-	extractedURL = extractPDFLinks(fileContent)            // Extract .pdf links
-	extractedURL = removeDuplicatesFromSlice(extractedURL) // Remove duplicate links
-	var downloadPDFWaitGroup sync.WaitGroup
 	outputDir := "PDFs/" // Directory to save PDFs
 	if !directoryExists(outputDir) {
 		createDirectory(outputDir, 0o755) // Create directory if not exists
 	}
 
-	for _, url := range extractedURL {
-		// Check if the url is valid.
+	downloader := NewDownloader(outputDir, *workers, *perHostQPS, *burst)
+	downloader.OnComplete = func(pdfURL string) {
+		if err := visitQueue.MarkDone(pdfURL); err != nil {
+			log.Printf("failed to mark %s done: %v", pdfURL, err)
+		}
+	}
+
+	if *warcPath != "" {
+		warcWriter, err := NewWARCWriter(*warcPath)
+		if err != nil {
+			log.Fatalf("failed to open WARC output %s: %v", *warcPath, err)
+		}
+		defer warcWriter.Close()
+		downloader.WARCWriter = warcWriter
+	}
+
+	var dashboardServer *http.Server
+	if *dashboardAddr != "" {
+		dashboardServer = NewDashboard(downloader, visitQueue).Start(*dashboardAddr)
+	}
+
+	for {
+		url, ok := visitQueue.Pop()
+		if !ok {
+			break // Pending queue is exhausted
+		}
+
+		// extractDocumentLinks always resolves against a base URL, so anything
+		// still invalid here isn't salvageable; skip it instead of downloading.
 		if !isUrlValid(url) {
-			log.Printf("Invalid URL found: %s", url)   // Log invalid URL
-			url = strings.TrimPrefix(url, "href=%22/") // Clean up URL
-			url = strings.TrimSuffix(url, "%22")       // Remove trailing quotes
-			url = "https://millcraft.com/" + url       // Prepend base URL if needed
+			log.Printf("skipping invalid URL from queue: %s", url)
+			continue
+		}
+		downloader.Enqueue(url) // Hand off to the worker pool; it rate-limits per host
+	}
+	downloader.Wait()
+
+	if dashboardServer != nil {
+		// The initial queue draining doesn't mean the operator is done: the
+		// dashboard's /enqueue lets them feed in more seed URLs for as long as
+		// this process stays up, so keep running until they ask us to stop.
+		log.Println("initial queue drained; dashboard still serving, press Ctrl+C to stop")
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := dashboardServer.Shutdown(ctx); err != nil {
+			log.Printf("dashboard shutdown error: %v", err)
 		}
-		// time.Sleep(100 * time.Millisecond) // Wait to avoid overwhelming server
-		downloadPDFWaitGroup.Add(1)
-		go downloadPDF(url, outputDir, &downloadPDFWaitGroup) // Try to download PDF
 	}
-	downloadPDFWaitGroup.Wait()
 }
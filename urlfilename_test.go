@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+// TestDocumentExtensionFromURL checks that the extension is read from the
+// URL's path, not thrown off by a trailing query string.
+func TestDocumentExtensionFromURL(t *testing.T) {
+	cases := []struct {
+		rawURL string
+		want   string
+	}{
+		{"https://millcraft.example/docs/sheet2.docx?rev=2", ".docx"},
+		{"https://millcraft.example/docs/sheet3.xlsx?rev=2&x=1", ".xlsx"},
+		{"https://millcraft.example/sds/sheet1.pdf", ".pdf"},
+		{"https://millcraft.example/sds/sheet1.PDF", ".pdf"},
+		{"https://millcraft.example/about", ".pdf"}, // No recognized extension; falls back to .pdf
+	}
+	for _, c := range cases {
+		if got := documentExtensionFromURL(c.rawURL); got != c.want {
+			t.Errorf("documentExtensionFromURL(%q) = %q, want %q", c.rawURL, got, c.want)
+		}
+	}
+}
+
+// TestUrlToFilenameKeepsQueryStringExtension checks that a query string
+// after the real extension doesn't cause urlToFilename (or downloadPDF,
+// which derives the extension separately via documentExtensionFromURL) to
+// mistake the download for a PDF.
+func TestUrlToFilenameKeepsQueryStringExtension(t *testing.T) {
+	rawURL := "https://millcraft.example/docs/sheet2.docx?rev=2"
+	filename := urlToFilename(rawURL)
+	if getFileExtension(filename) == ".pdf" {
+		t.Errorf("urlToFilename(%q) = %q, filename extension resolved to .pdf even though the source URL is a .docx", rawURL, filename)
+	}
+	if ext := documentExtensionFromURL(rawURL); ext != ".docx" {
+		t.Errorf("documentExtensionFromURL(%q) = %q, want .docx", rawURL, ext)
+	}
+}
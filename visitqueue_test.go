@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestVisitQueuePushPopOrder checks that Pop returns pushed URLs in the same
+// FIFO order they were written.
+func TestVisitQueuePushPopOrder(t *testing.T) {
+	dir := t.TempDir()
+	queue, err := NewVisitQueue(filepath.Join(dir, "queue.bin"), filepath.Join(dir, "completed.txt"))
+	if err != nil {
+		t.Fatalf("NewVisitQueue failed: %v", err)
+	}
+	defer queue.Close()
+
+	want := []string{"https://a.example/1", "https://a.example/2", "https://a.example/3"}
+	for _, url := range want {
+		if err := queue.Push(url); err != nil {
+			t.Fatalf("Push(%q) failed: %v", url, err)
+		}
+	}
+
+	for _, wantURL := range want {
+		gotURL, ok := queue.Pop()
+		if !ok {
+			t.Fatalf("Pop returned ok=false before exhausting %d pushed URLs", len(want))
+		}
+		if gotURL != wantURL {
+			t.Errorf("Pop() = %q, want %q", gotURL, wantURL)
+		}
+	}
+	if _, ok := queue.Pop(); ok {
+		t.Error("Pop() returned ok=true after the queue was exhausted")
+	}
+}
+
+// TestVisitQueueMarkDoneSkipsOnResume simulates a crash and restart: URLs
+// marked done before the restart must not be handed out again once the
+// queue is reopened from the same files.
+func TestVisitQueueMarkDoneSkipsOnResume(t *testing.T) {
+	dir := t.TempDir()
+	queuePath := filepath.Join(dir, "queue.bin")
+	completedPath := filepath.Join(dir, "completed.txt")
+
+	queue, err := NewVisitQueue(queuePath, completedPath)
+	if err != nil {
+		t.Fatalf("NewVisitQueue failed: %v", err)
+	}
+	for _, url := range []string{"https://a.example/1", "https://a.example/2"} {
+		if err := queue.Push(url); err != nil {
+			t.Fatalf("Push(%q) failed: %v", url, err)
+		}
+	}
+	if err := queue.MarkDone("https://a.example/1"); err != nil {
+		t.Fatalf("MarkDone failed: %v", err)
+	}
+	if err := queue.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	resumed, err := NewVisitQueue(queuePath, completedPath)
+	if err != nil {
+		t.Fatalf("NewVisitQueue (resume) failed: %v", err)
+	}
+	defer resumed.Close()
+
+	gotURL, ok := resumed.Pop()
+	if !ok {
+		t.Fatal("Pop() returned ok=false, expected the one un-done URL to remain")
+	}
+	if gotURL != "https://a.example/2" {
+		t.Errorf("Pop() = %q, want %q (the already-done URL should have been skipped)", gotURL, "https://a.example/2")
+	}
+	if _, ok := resumed.Pop(); ok {
+		t.Error("Pop() returned ok=true after the only remaining URL was consumed")
+	}
+}
+
+// TestVisitQueuePopHandlesTruncatedRecord ensures a partially-written final
+// record (e.g. from a crash mid-Push) is treated as the end of the queue
+// instead of panicking or returning garbage.
+func TestVisitQueuePopHandlesTruncatedRecord(t *testing.T) {
+	dir := t.TempDir()
+	queuePath := filepath.Join(dir, "queue.bin")
+	completedPath := filepath.Join(dir, "completed.txt")
+
+	queue, err := NewVisitQueue(queuePath, completedPath)
+	if err != nil {
+		t.Fatalf("NewVisitQueue failed: %v", err)
+	}
+	if err := queue.Push("https://a.example/1"); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if err := queue.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Append a length prefix that claims more data than actually follows,
+	// simulating a crash partway through writing the next record.
+	file, err := os.OpenFile(queuePath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to reopen queue file: %v", err)
+	}
+	if _, err := file.Write([]byte{0x00, 0x00, 0x00, 0x10}); err != nil {
+		t.Fatalf("failed to append truncated record: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("failed to close queue file: %v", err)
+	}
+
+	resumed, err := NewVisitQueue(queuePath, completedPath)
+	if err != nil {
+		t.Fatalf("NewVisitQueue (resume) failed: %v", err)
+	}
+	defer resumed.Close()
+
+	gotURL, ok := resumed.Pop()
+	if !ok || gotURL != "https://a.example/1" {
+		t.Fatalf("Pop() = (%q, %v), want (%q, true)", gotURL, ok, "https://a.example/1")
+	}
+	if _, ok := resumed.Pop(); ok {
+		t.Error("Pop() returned ok=true for a truncated trailing record")
+	}
+}
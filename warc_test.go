@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readWARCRecords decodes every gzip member in path as a separate WARC
+// record and returns each one's raw (header+payload) text, in file order.
+// It walks the raw bytes with a bytes.Reader (rather than reusing one
+// gzip.Reader over the file) so each member's exact compressed length can
+// be measured from how much the reader consumed.
+func readWARCRecords(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read WARC file: %v", err)
+	}
+
+	var records []string
+	offset := 0
+	for offset < len(data) {
+		byteReader := bytes.NewReader(data[offset:])
+		gzipReader, err := gzip.NewReader(byteReader)
+		if err != nil {
+			t.Fatalf("failed to open gzip member %d: %v", len(records), err)
+		}
+		gzipReader.Multistream(false) // Each record is its own gzip member; don't slurp the rest of the file as one stream
+
+		var decoded bytes.Buffer
+		if _, err := io.Copy(&decoded, gzipReader); err != nil {
+			t.Fatalf("failed to decode gzip member %d: %v", len(records), err)
+		}
+		gzipReader.Close()
+		records = append(records, decoded.String())
+		offset += len(data[offset:]) - byteReader.Len() // Advance past exactly the bytes this member consumed
+	}
+	return records
+}
+
+// TestWARCWriterRecordFraming checks that NewWARCWriter and WriteExchange
+// produce one gzip member per record, each with the required WARC headers
+// and a CRLF-terminated payload matching what was written in.
+func TestWARCWriterRecordFraming(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.warc.gz")
+
+	writer, err := NewWARCWriter(path)
+	if err != nil {
+		t.Fatalf("NewWARCWriter failed: %v", err)
+	}
+
+	resp := &http.Response{
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/pdf"}},
+	}
+	body := []byte("%PDF-1.4 fake body")
+	if err := writer.WriteExchange("https://millcraft.example/sds/sheet1.pdf", resp, body); err != nil {
+		t.Fatalf("WriteExchange failed: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	records := readWARCRecords(t, path)
+	if len(records) != 3 {
+		t.Fatalf("got %d WARC records, want 3 (warcinfo, request, response)", len(records))
+	}
+
+	wantTypes := []string{"warcinfo", "request", "response"}
+	for i, record := range records {
+		if !strings.HasPrefix(record, "WARC/1.1\r\n") {
+			t.Errorf("record %d doesn't start with the WARC/1.1 version line: %q", i, record)
+		}
+		if !strings.Contains(record, "WARC-Type: "+wantTypes[i]+"\r\n") {
+			t.Errorf("record %d missing WARC-Type: %s header: %q", i, wantTypes[i], record)
+		}
+		if !strings.Contains(record, "WARC-Record-ID: <urn:uuid:") {
+			t.Errorf("record %d missing a WARC-Record-ID header: %q", i, record)
+		}
+		if !strings.Contains(record, "Content-Length: ") {
+			t.Errorf("record %d missing a Content-Length header: %q", i, record)
+		}
+		if !strings.HasSuffix(record, "\r\n\r\n") {
+			t.Errorf("record %d doesn't end with the two terminating CRLFs: %q", i, record)
+		}
+	}
+
+	requestRecord, responseRecord := records[1], records[2]
+	if !strings.Contains(requestRecord, "WARC-Target-URI: https://millcraft.example/sds/sheet1.pdf\r\n") {
+		t.Errorf("request record missing WARC-Target-URI: %q", requestRecord)
+	}
+	if !strings.Contains(requestRecord, "GET https://millcraft.example/sds/sheet1.pdf HTTP/1.1\r\n") {
+		t.Errorf("request record payload missing the request line: %q", requestRecord)
+	}
+	if !strings.Contains(responseRecord, "WARC-Concurrent-To: <urn:uuid:") {
+		t.Errorf("response record missing WARC-Concurrent-To: %q", responseRecord)
+	}
+	if !strings.Contains(responseRecord, "HTTP/1.1 200 OK\r\n") {
+		t.Errorf("response record payload missing the status line: %q", responseRecord)
+	}
+	if !strings.Contains(responseRecord, string(body)) {
+		t.Errorf("response record payload doesn't contain the downloaded body: %q", responseRecord)
+	}
+}